@@ -0,0 +1,58 @@
+package mummy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+)
+
+func TestFeedDumpDecimalRoundTrip(t *testing.T) {
+	big1e40 := new(big.Int).Exp(big.NewInt(10), big.NewInt(40), nil)
+
+	cases := []*big.Rat{
+		big.NewRat(0, 1),
+		big.NewRat(1, 1),
+		big.NewRat(-1, 1),
+		big.NewRat(314159, 100000),
+		big.NewRat(-314159, 100000),
+		big.NewRat(7, 1000),
+		big.NewRat(1, 2),
+		big.NewRat(1, 4),
+		big.NewRat(1, 5),
+		big.NewRat(-1, 8),
+		new(big.Rat).SetFrac(big.NewInt(123), big1e40),
+		new(big.Rat).SetFrac(big.NewInt(-123), big1e40),
+	}
+
+	for _, want := range cases {
+		var buf bytes.Buffer
+		if err := feed_decimal(&buf, binary.BigEndian, want); err != nil {
+			t.Fatalf("feed_decimal(%s): %v", want.RatString(), err)
+		}
+
+		// drop the type tag, as a real decode dispatcher would have
+		// already consumed it.
+		buf.Next(1)
+
+		got, err := dump_decimal(&buf, binary.BigEndian)
+		if err != nil {
+			t.Fatalf("dump_decimal(%s): %v", want.RatString(), err)
+		}
+
+		if got.Cmp(want) != 0 {
+			t.Errorf("round trip of %s produced %s", want.RatString(), got.RatString())
+		}
+	}
+}
+
+func TestFeedDecimalRejectsRepeatingDecimal(t *testing.T) {
+	var buf bytes.Buffer
+	err := feed_decimal(&buf, binary.BigEndian, big.NewRat(1, 3))
+	if err == nil {
+		t.Fatal("expected an error for a denominator with a prime factor other than 2 or 5")
+	}
+	if _, ok := err.(*DenominatorError); !ok {
+		t.Fatalf("expected a *DenominatorError, got %T", err)
+	}
+}
@@ -1,138 +1,262 @@
 package mummy
 
 import (
-	"encoding/binary"
-	"errors"
 	"bytes"
-	"math"
+	"encoding/binary"
+	"fmt"
 	"math/big"
+	"time"
 )
 
-
 func feed_null(b *bytes.Buffer) error {
 	return b.WriteByte(MUMMY_TYPE_NULL)
 }
 
 func feed_bool(b *bytes.Buffer, val bool) error {
+	if err := b.WriteByte(MUMMY_TYPE_BOOL); err != nil {
+		return err
+	}
 	if val {
-		return b.Write([]byte{MUMMY_TYPE_BOOL, 1})
-	} else {
-		return b.Write([]byte{MUMMY_TYPE_BOOL, 1})
+		return b.WriteByte(1)
 	}
+	return b.WriteByte(0)
 }
 
-func feed_int(b *bytes.Buffer, num int64) error {
+func feed_int(b *bytes.Buffer, order binary.ByteOrder, num int64) error {
 	var taip uint8
+	var val interface{}
 	switch {
 	case -128 < num && num < 128:
 		taip = MUMMY_TYPE_CHAR
-		val := int8(num)
+		val = int8(num)
 	case -32768 <= num && num < 32768:
 		taip = MUMMY_TYPE_SHORT
-		val := int16(num)
+		val = int16(num)
 	case -2147483648 <= num && num < 2147483648:
 		taip = MUMMY_TYPE_INT
-		val := int32(num)
+		val = int32(num)
 	default:
 		taip = MUMMY_TYPE_LONG
-		val := num
+		val = num
 	}
 
-	return binary.Write(b, binary.BigEndian, val)
+	if err := b.WriteByte(taip); err != nil {
+		return err
+	}
+	return binary.Write(b, order, val)
 }
 
-func feed_huge(b *bytes.Buffer, data big.Int) error {
+func feed_huge(b *bytes.Buffer, order binary.ByteOrder, data big.Int) error {
 	if err := b.WriteByte(MUMMY_TYPE_HUGE); err != nil {
 		return err
 	}
 	buf := data.Bytes()
-	if err := binary.Write(b, binary.BigEndian, int32(len(buf))); err != nil {
+	if err := binary.Write(b, order, int32(len(buf))); err != nil {
 		return err
 	}
 	_, err := b.Write(buf)
 	return err
 }
 
-func feed_float(b *bytes.Buffer, f float64) error {
+func feed_float(b *bytes.Buffer, order binary.ByteOrder, f float64) error {
 	if err := b.WriteByte(MUMMY_TYPE_FLOAT); err != nil {
 		return err
 	}
-	return binary.Write(b, binary.BigEndian, f)
+	return binary.Write(b, order, f)
 }
 
-func feed_string(b *bytes.Buffer, data []byte) error {
+func feed_string(b *bytes.Buffer, order binary.ByteOrder, data []byte) error {
 	var taip uint8
+	var size interface{}
 	l := len(data)
 	switch {
 	case l < 256:
 		taip = MUMMY_TYPE_SHORTSTR
-		size := uint8(l)
+		size = uint8(l)
 	case l < 65536:
 		taip = MUMMY_TYPE_MEDSTR
-		size := uint16(l)
+		size = uint16(l)
 	default:
-		taip = MUMM_TYPE_LONGSTR
-		size := uint32(l)
+		taip = MUMMY_TYPE_LONGSTR
+		size = uint32(l)
 	}
 
 	if err := b.WriteByte(taip); err != nil {
 		return err
 	}
-	if err := binary.Write(b, binary.BigEndian, size); err != nil {
+	if err := binary.Write(b, order, size); err != nil {
 		return err
 	}
 	_, err := b.Write(data)
 	return err
 }
 
-func feed_utf8(b *bytes.Buffer, data string) error {
+func feed_utf8(b *bytes.Buffer, order binary.ByteOrder, data string) error {
 	buf := []byte(data)
 	var taip uint8
+	var size interface{}
 	l := len(buf)
 	switch {
 	case l < 256:
 		taip = MUMMY_TYPE_SHORTUTF8
-		size := uint8(l)
+		size = uint8(l)
 	case l < 65536:
 		taip = MUMMY_TYPE_MEDUTF8
-		size := uint16(l)
+		size = uint16(l)
 	default:
-		taip = MUMM_TYPE_LONGUTF8
-		size := uint32(l)
+		taip = MUMMY_TYPE_LONGUTF8
+		size = uint32(l)
 	}
 
 	if err := b.WriteByte(taip); err != nil {
 		return err
 	}
-	if err := binary.Write(b, binary.BigEndian, size); err != nil {
+	if err := binary.Write(b, order, size); err != nil {
 		return err
 	}
-	_, err = b.Write(buf)
+	_, err := b.Write(buf)
 	return err
 }
 
-/*
-func feed_decimal(b *bytes.Buffer, data big.Rat) error {
-	var m big.Int
-	d := data.Denom()
-	m := big.Int{}.Mod(d, big.NewInt(10))
-	if m.Int64() != 0 {
-		return errors.New("denominator must be a multiple of 10")
+// feed_decimal encodes a *big.Rat as MUMMY_TYPE_DECIMAL: a sign byte, a
+// signed 16-bit exponent (minus the number of decimal places, so the value
+// is the magnitude times ten to that power), and a length-prefixed
+// big-endian two's-complement magnitude of the rescaled numerator.
+// big.Rat stores its value reduced, so an ordinary finite decimal like
+// 1/2 has a denominator of 2, not 10; decimalPlaces finds the smallest
+// power of ten the value can be rescaled to exactly, and the numerator is
+// scaled up to match. Only a denominator with prime factors other than 2
+// and 5 (a repeating decimal, e.g. 1/3) can't be represented, and comes
+// back as a *DenominatorError.
+func feed_decimal(b *bytes.Buffer, order binary.ByteOrder, data *big.Rat) error {
+	denom := data.Denom()
+
+	places, ok := decimalPlaces(denom)
+	if !ok {
+		return &DenominatorError{Denom: denom}
 	}
 
-	// char of sign (0 positive, 1 negative)
-	var isNeg uint8
-	switch {
-	case data.Sign() < 0:
-		is_neg = 1
-	default:
-		is_neg = 0
+	var sign uint8
+	if data.Sign() < 0 {
+		sign = 1
 	}
-	if err := b.WriteByte(is_neg); err != nil {
+
+	if err := b.WriteByte(MUMMY_TYPE_DECIMAL); err != nil {
+		return err
+	}
+	if err := b.WriteByte(sign); err != nil {
+		return err
+	}
+	if err := binary.Write(b, order, int16(-places)); err != nil {
+		return err
+	}
+
+	// num/denom rescaled to a denominator of 10^places is num*10^places/denom;
+	// decimalPlaces guarantees that division is exact.
+	mag := new(big.Int).Abs(data.Num())
+	mag.Mul(mag, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil))
+	mag.Div(mag, denom)
+
+	magBytes := mag.Bytes()
+	if len(magBytes) > 0 && magBytes[0]&0x80 != 0 {
+		// keep the non-negative magnitude from reading as negative in
+		// two's complement
+		magBytes = append([]byte{0}, magBytes...)
+	}
+	if err := binary.Write(b, order, int32(len(magBytes))); err != nil {
 		return err
 	}
+	_, err := b.Write(magBytes)
+	return err
+}
+
+// feed_varint encodes an int64 as MUMMY_TYPE_VARINT: a zig-zag encoded
+// LEB128-style varint, 7 bits per byte little-endian with the high bit
+// set on all but the last byte. This is the same scheme as Go's
+// encoding/binary PutVarint. Values in -64..63 take a single payload
+// byte, so with the type tag they take two bytes, the same as feed_int's
+// CHAR layout; the varint form is smaller than feed_int's only for some
+// magnitudes that need INT or LONG there, and larger for others. The
+// varint byte layout is fixed regardless of the encoder's configured
+// byte order.
+func feed_varint(b *bytes.Buffer, num int64) error {
+	if err := b.WriteByte(MUMMY_TYPE_VARINT); err != nil {
+		return err
+	}
+
+	u := uint64((num << 1) ^ (num >> 63))
+	for u >= 0x80 {
+		if err := b.WriteByte(byte(u) | 0x80); err != nil {
+			return err
+		}
+		u >>= 7
+	}
+	return b.WriteByte(byte(u))
+}
+
+// feed_timestamp encodes a time.Time as MUMMY_TYPE_TIMESTAMP: a signed
+// 64-bit count of seconds since the Unix epoch (UTC) followed by a signed
+// 32-bit count of nanoseconds within that second.
+func feed_timestamp(b *bytes.Buffer, order binary.ByteOrder, t time.Time) error {
+	if err := b.WriteByte(MUMMY_TYPE_TIMESTAMP); err != nil {
+		return err
+	}
+	if err := binary.Write(b, order, t.Unix()); err != nil {
+		return err
+	}
+	return binary.Write(b, order, int32(t.Nanosecond()))
+}
+
+// decimalPlaces returns the smallest k such that a fraction with
+// denominator n can be rescaled to an exact fraction with denominator
+// 10^k: n's prime factorization must contain only 2s and 5s, and k is
+// the larger of the two factors' exponents. This is computed by exact
+// repeated integer division rather than math.Log, which loses precision
+// for large n. ok is false if n has any other prime factor, meaning its
+// decimal expansion repeats and has no exact MUMMY_TYPE_DECIMAL
+// representation.
+func decimalPlaces(n *big.Int) (k int, ok bool) {
+	rem := new(big.Int).Set(n)
+	two, five := big.NewInt(2), big.NewInt(5)
+	q, m := new(big.Int), new(big.Int)
+
+	var pow2 int
+	for {
+		q.QuoRem(rem, two, m)
+		if m.Sign() != 0 {
+			break
+		}
+		rem.Set(q)
+		pow2++
+	}
+
+	var pow5 int
+	for {
+		q.QuoRem(rem, five, m)
+		if m.Sign() != 0 {
+			break
+		}
+		rem.Set(q)
+		pow5++
+	}
+
+	if rem.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	if pow2 > pow5 {
+		return pow2, true
+	}
+	return pow5, true
+}
+
+// DenominatorError is returned by feed_decimal when a *big.Rat's
+// denominator has a prime factor other than 2 or 5, so the value cannot be
+// rescaled to a power-of-ten denominator and has no MUMMY_TYPE_DECIMAL
+// representation.
+type DenominatorError struct {
+	Denom *big.Int
+}
 
-	// signed short of decimal point position
-	pow := int(math.Log(float64(d.Int64())))
+func (e *DenominatorError) Error() string {
+	return fmt.Sprintf("mummy: decimal denominator %s has a prime factor other than 2 or 5", e.Denom.String())
 }
-*/
@@ -0,0 +1,65 @@
+package mummy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestEncoderDecoderLittleEndian(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.SetByteOrder(binary.LittleEndian)
+	if err := enc.Encode(int64(70000)); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	dec := NewDecoder(&buf)
+	dec.SetByteOrder(binary.LittleEndian)
+	var got int64
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got != 70000 {
+		t.Errorf("got %d, want 70000", got)
+	}
+}
+
+func TestDecoderDefaultsToBigEndian(t *testing.T) {
+	data, err := Marshal(int64(70000))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got int64
+	if err := NewDecoder(bytes.NewReader(data)).Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got != 70000 {
+		t.Errorf("got %d, want 70000", got)
+	}
+}
+
+func TestFeedBoolDistinguishesTrueFalse(t *testing.T) {
+	var trueBuf, falseBuf bytes.Buffer
+	if err := feed_bool(&trueBuf, true); err != nil {
+		t.Fatalf("feed_bool(true): %v", err)
+	}
+	if err := feed_bool(&falseBuf, false); err != nil {
+		t.Fatalf("feed_bool(false): %v", err)
+	}
+	if bytes.Equal(trueBuf.Bytes(), falseBuf.Bytes()) {
+		t.Fatal("feed_bool encoded true and false identically")
+	}
+
+	var gotTrue, gotFalse bool
+	if err := Unmarshal(trueBuf.Bytes(), &gotTrue); err != nil {
+		t.Fatalf("Unmarshal(true): %v", err)
+	}
+	if err := Unmarshal(falseBuf.Bytes(), &gotFalse); err != nil {
+		t.Fatalf("Unmarshal(false): %v", err)
+	}
+	if !gotTrue || gotFalse {
+		t.Fatalf("got true=%v false=%v", gotTrue, gotFalse)
+	}
+}
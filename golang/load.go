@@ -0,0 +1,151 @@
+package mummy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+)
+
+// dump_decimal reads back a value encoded by feed_decimal. The type tag
+// byte is assumed to have already been consumed by the caller.
+func dump_decimal(r io.Reader, order binary.ByteOrder) (*big.Rat, error) {
+	var sign uint8
+	if err := binary.Read(r, order, &sign); err != nil {
+		return nil, err
+	}
+
+	var exp int16
+	if err := binary.Read(r, order, &exp); err != nil {
+		return nil, err
+	}
+
+	var size int32
+	if err := binary.Read(r, order, &size); err != nil {
+		return nil, err
+	}
+	if err := validate_length(r, int64(size)); err != nil {
+		return nil, err
+	}
+
+	mag := make([]byte, size)
+	if _, err := io.ReadFull(r, mag); err != nil {
+		return nil, err
+	}
+
+	num := new(big.Int).SetBytes(mag)
+	if sign == 1 {
+		num.Neg(num)
+	}
+
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil)
+	return new(big.Rat).SetFrac(num, denom), nil
+}
+
+// dump_varint reads back a value encoded by feed_varint, capping at 10
+// bytes (enough for a full 64-bit zig-zag varint) and erroring past that
+// rather than looping forever on a malformed stream. The varint byte
+// layout is fixed regardless of the decoder's configured byte order.
+func dump_varint(r io.Reader) (int64, error) {
+	var u uint64
+	var buf [1]byte
+	for shift := uint(0); ; shift += 7 {
+		if shift >= 70 {
+			return 0, fmt.Errorf("mummy: varint exceeds 10 bytes")
+		}
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		u |= uint64(buf[0]&0x7f) << shift
+		if buf[0] < 0x80 {
+			break
+		}
+	}
+	return int64(u>>1) ^ -int64(u&1), nil
+}
+
+// dump_huge reads back a value encoded by feed_huge. The type tag byte is
+// assumed to have already been consumed by the caller.
+func dump_huge(r io.Reader, order binary.ByteOrder) (*big.Int, error) {
+	var size int32
+	if err := binary.Read(r, order, &size); err != nil {
+		return nil, err
+	}
+	if err := validate_length(r, int64(size)); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// dump_timestamp reads back a value encoded by feed_timestamp. The type
+// tag byte is assumed to have already been consumed by the caller.
+func dump_timestamp(r io.Reader, order binary.ByteOrder) (time.Time, error) {
+	var sec int64
+	if err := binary.Read(r, order, &sec); err != nil {
+		return time.Time{}, err
+	}
+
+	var nsec int32
+	if err := binary.Read(r, order, &nsec); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(sec, int64(nsec)).UTC(), nil
+}
+
+// dump_string reads back a value encoded by feed_string or feed_utf8 given
+// its already-consumed type tag, returning the raw payload bytes.
+func dump_string(r io.Reader, order binary.ByteOrder, tag uint8) ([]byte, error) {
+	var size uint32
+	switch tag {
+	case MUMMY_TYPE_SHORTSTR, MUMMY_TYPE_SHORTUTF8:
+		var n uint8
+		if err := binary.Read(r, order, &n); err != nil {
+			return nil, err
+		}
+		size = uint32(n)
+	case MUMMY_TYPE_MEDSTR, MUMMY_TYPE_MEDUTF8:
+		var n uint16
+		if err := binary.Read(r, order, &n); err != nil {
+			return nil, err
+		}
+		size = uint32(n)
+	default:
+		if err := binary.Read(r, order, &size); err != nil {
+			return nil, err
+		}
+	}
+	if err := validate_length(r, int64(size)); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// validate_length checks a decoded byte-length prefix before the caller
+// allocates a buffer sized by it: a negative length (the field is signed
+// in the wire format, e.g. dump_decimal's and dump_huge's int32 size) or
+// one bigger than the stream actually has left (a bogus LONGSTR length,
+// for instance) would otherwise reach make() and either panic or drive a
+// multi-gigabyte allocation before the eventual read failure. Mirrors the
+// read_count guard used for list/dict element counts.
+func validate_length(r io.Reader, size int64) error {
+	if size < 0 {
+		return fmt.Errorf("mummy: negative length %d", size)
+	}
+	if lr, ok := r.(interface{ Len() int }); ok && size > int64(lr.Len()) {
+		return fmt.Errorf("mummy: length %d exceeds %d remaining bytes", size, lr.Len())
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package mummy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+var (
+	bigIntType = reflect.TypeOf((*big.Int)(nil))
+	bigRatType = reflect.TypeOf((*big.Rat)(nil))
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// Marshal encodes v as MUMMY, walking it with reflect and dispatching to
+// the feed_* helpers for primitives. Slices and arrays become
+// MUMMY_TYPE_LIST, maps and structs become MUMMY_TYPE_DICT (struct fields
+// keyed by name, or by a `mummy:"name,omitempty"` tag), []byte becomes a
+// MUMMY string, *big.Int becomes MUMMY_TYPE_HUGE, *big.Rat becomes
+// MUMMY_TYPE_DECIMAL, and time.Time becomes MUMMY_TYPE_TIMESTAMP.
+// Multi-byte fields are written big-endian; use an Encoder to pick a
+// different byte order.
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := feed_value(&buf, binary.BigEndian, reflect.ValueOf(v), false); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// feed_value encodes v, using MUMMY_TYPE_VARINT instead of the fixed-width
+// CHAR/SHORT/INT/LONG tags for integers when varint is true.
+func feed_value(b *bytes.Buffer, order binary.ByteOrder, v reflect.Value, varint bool) error {
+	if !v.IsValid() {
+		return feed_null(b)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return feed_null(b)
+		}
+		return feed_value(b, order, v.Elem(), varint)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return feed_null(b)
+		}
+		switch v.Type() {
+		case bigIntType:
+			return feed_huge(b, order, *v.Interface().(*big.Int))
+		case bigRatType:
+			return feed_decimal(b, order, v.Interface().(*big.Rat))
+		}
+		return feed_value(b, order, v.Elem(), varint)
+
+	case reflect.Bool:
+		return feed_bool(b, v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if varint {
+			return feed_varint(b, v.Int())
+		}
+		return feed_int(b, order, v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if varint {
+			return feed_varint(b, int64(v.Uint()))
+		}
+		return feed_int(b, order, int64(v.Uint()))
+
+	case reflect.Float32, reflect.Float64:
+		return feed_float(b, order, v.Float())
+
+	case reflect.String:
+		return feed_utf8(b, order, v.String())
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return feed_string(b, order, v.Bytes())
+		}
+		return feed_list(b, order, v, varint)
+
+	case reflect.Array:
+		return feed_list(b, order, v, varint)
+
+	case reflect.Map:
+		return feed_dict(b, order, v, varint)
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return feed_timestamp(b, order, v.Interface().(time.Time))
+		}
+		return feed_struct(b, order, v, varint)
+
+	default:
+		return fmt.Errorf("mummy: cannot encode %s", v.Type())
+	}
+}
+
+func feed_list(b *bytes.Buffer, order binary.ByteOrder, v reflect.Value, varint bool) error {
+	if err := b.WriteByte(MUMMY_TYPE_LIST); err != nil {
+		return err
+	}
+	if err := binary.Write(b, order, int32(v.Len())); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := feed_value(b, order, v.Index(i), varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func feed_dict(b *bytes.Buffer, order binary.ByteOrder, v reflect.Value, varint bool) error {
+	keys := v.MapKeys()
+
+	if err := b.WriteByte(MUMMY_TYPE_DICT); err != nil {
+		return err
+	}
+	if err := binary.Write(b, order, int32(len(keys))); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := feed_value(b, order, k, varint); err != nil {
+			return err
+		}
+		if err := feed_value(b, order, v.MapIndex(k), varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func feed_struct(b *bytes.Buffer, order binary.ByteOrder, v reflect.Value, varint bool) error {
+	t := v.Type()
+
+	type field struct {
+		name string
+		val  reflect.Value
+	}
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		name, omitempty, skip := fieldTagInfo(t.Field(i))
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		if omitempty && isEmptyValue(fv) {
+			continue
+		}
+		fields = append(fields, field{name, fv})
+	}
+
+	if err := b.WriteByte(MUMMY_TYPE_DICT); err != nil {
+		return err
+	}
+	if err := binary.Write(b, order, int32(len(fields))); err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if err := feed_utf8(b, order, f.name); err != nil {
+			return err
+		}
+		if err := feed_value(b, order, f.val, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
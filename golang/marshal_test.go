@@ -0,0 +1,114 @@
+package mummy
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `mummy:"city"`
+	Zip  string `mummy:"zip,omitempty"`
+}
+
+type person struct {
+	Name     string            `mummy:"name"`
+	Age      int               `mummy:"age"`
+	Balance  *big.Rat          `mummy:"balance"`
+	Huge     *big.Int          `mummy:"huge"`
+	Tags     []string          `mummy:"tags"`
+	Address  address           `mummy:"address"`
+	Created  time.Time         `mummy:"created"`
+	Meta     map[string]string `mummy:"meta,omitempty"`
+	internal string
+}
+
+func TestMarshalUnmarshalStruct(t *testing.T) {
+	want := person{
+		Name:    "Ada",
+		Age:     36,
+		Balance: big.NewRat(521, 100),
+		Huge:    big.NewInt(123456789),
+		Tags:    []string{"math", "computing"},
+		Address: address{City: "London"},
+		Created: time.Unix(1234567890, 0).UTC(),
+	}
+
+	data, err := Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got person
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Name != want.Name || got.Age != want.Age {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got.Balance.Cmp(want.Balance) != 0 {
+		t.Errorf("Balance = %s, want %s", got.Balance.RatString(), want.Balance.RatString())
+	}
+	if got.Huge.Cmp(want.Huge) != 0 {
+		t.Errorf("Huge = %s, want %s", got.Huge.String(), want.Huge.String())
+	}
+	if !reflect.DeepEqual(got.Tags, want.Tags) {
+		t.Errorf("Tags = %v, want %v", got.Tags, want.Tags)
+	}
+	if got.Address != want.Address {
+		t.Errorf("Address = %+v, want %+v", got.Address, want.Address)
+	}
+	if !got.Created.Equal(want.Created) {
+		t.Errorf("Created = %v, want %v", got.Created, want.Created)
+	}
+	if got.Meta != nil {
+		t.Errorf("Meta = %v, want nil (omitempty)", got.Meta)
+	}
+}
+
+func TestMarshalUnmarshalInterface(t *testing.T) {
+	data, err := Marshal(map[string]interface{}{
+		"n": int64(42),
+		"s": "hello",
+		"l": []interface{}{int64(1), int64(2), int64(3)},
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got interface{}
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	dict, ok := got.(map[interface{}]interface{})
+	if !ok {
+		t.Fatalf("got %T, want map[interface{}]interface{}", got)
+	}
+	if dict["n"] != int64(42) {
+		t.Errorf("dict[\"n\"] = %v, want 42", dict["n"])
+	}
+	if dict["s"] != "hello" {
+		t.Errorf("dict[\"s\"] = %v, want \"hello\"", dict["s"])
+	}
+	list, ok := dict["l"].([]interface{})
+	if !ok || len(list) != 3 {
+		t.Fatalf("dict[\"l\"] = %v, want a 3-element list", dict["l"])
+	}
+}
+
+func TestUnmarshalRejectsNegativeListCount(t *testing.T) {
+	data := []byte{MUMMY_TYPE_LIST, 0xff, 0xff, 0xff, 0xff} // count = -1, big-endian
+
+	var slice []int64
+	if err := Unmarshal(data, &slice); err == nil {
+		t.Fatal("expected an error decoding a negative-length list into a slice, got nil")
+	}
+
+	var any interface{}
+	if err := Unmarshal(data, &any); err == nil {
+		t.Fatal("expected an error decoding a negative-length list into interface{}, got nil")
+	}
+}
@@ -0,0 +1,195 @@
+package mummy
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Huge wraps a *big.Int so it can flow directly into and out of
+// database/sql, round-tripping through its canonical base-10 string.
+type Huge struct {
+	Int *big.Int
+}
+
+// Value implements driver.Valuer.
+func (h Huge) Value() (driver.Value, error) {
+	if h.Int == nil {
+		return nil, nil
+	}
+	return h.Int.String(), nil
+}
+
+// Scan implements sql.Scanner, accepting []byte, string, int64 and
+// float64 source values.
+func (h *Huge) Scan(src interface{}) error {
+	if src == nil {
+		h.Int = nil
+		return nil
+	}
+
+	s, err := decimalSourceString(src)
+	if err != nil {
+		return fmt.Errorf("mummy: Huge.Scan: %w", err)
+	}
+
+	neg, intPart, fracPart, err := parseDecimalString(s)
+	if err != nil {
+		return fmt.Errorf("mummy: Huge.Scan: %w", err)
+	}
+	if fracPart != "" {
+		return fmt.Errorf("mummy: Huge.Scan: %q is not an integer", s)
+	}
+
+	n := parseBigDigits(intPart)
+	if neg {
+		n.Neg(n)
+	}
+	h.Int = n
+	return nil
+}
+
+// Decimal wraps a *big.Rat so it can flow directly into and out of
+// database/sql, round-tripping through its canonical base-10 string.
+type Decimal struct {
+	Rat *big.Rat
+}
+
+// Value implements driver.Valuer.
+func (d Decimal) Value() (driver.Value, error) {
+	if d.Rat == nil {
+		return nil, nil
+	}
+
+	places, ok := decimalPlaces(d.Rat.Denom())
+	if !ok {
+		// Repeating decimal (denominator has a prime factor other than 2
+		// or 5): can't happen for a Decimal built by Scan, but a caller
+		// could construct one by hand. There's no exact base-10 text
+		// representation, so report it rather than guess a precision and
+		// write a lossy approximation.
+		return nil, &DenominatorError{Denom: d.Rat.Denom()}
+	}
+	return d.Rat.FloatString(places), nil
+}
+
+// Scan implements sql.Scanner, accepting []byte, string, int64 and
+// float64 source values.
+func (d *Decimal) Scan(src interface{}) error {
+	if src == nil {
+		d.Rat = nil
+		return nil
+	}
+
+	s, err := decimalSourceString(src)
+	if err != nil {
+		return fmt.Errorf("mummy: Decimal.Scan: %w", err)
+	}
+
+	neg, intPart, fracPart, err := parseDecimalString(s)
+	if err != nil {
+		return fmt.Errorf("mummy: Decimal.Scan: %w", err)
+	}
+
+	num := parseBigDigits(intPart + fracPart)
+	if neg {
+		num.Neg(num)
+	}
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fracPart))), nil)
+	d.Rat = new(big.Rat).SetFrac(num, denom)
+	return nil
+}
+
+// decimalSourceString normalizes a sql.Scanner source value to its
+// base-10 text representation.
+func decimalSourceString(src interface{}) (string, error) {
+	switch v := src.(type) {
+	case []byte:
+		return string(v), nil
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported Scan source type %T", src)
+	}
+}
+
+// parseDecimalString splits a base-10 string like "-123.45" into its sign
+// and digit runs either side of the decimal point.
+func parseDecimalString(s string) (neg bool, intPart, fracPart string, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return false, "", "", fmt.Errorf("empty value")
+	}
+
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, _ = strings.Cut(s, ".")
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	for _, part := range [2]string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return false, "", "", fmt.Errorf("invalid digit %q in %q", c, s)
+			}
+		}
+	}
+	return neg, intPart, fracPart, nil
+}
+
+// digitChunk is the number of decimal digits parseBigDigits accumulates
+// into a uint64 before folding it into the big.Int total, so a 200-digit
+// number costs ~11 big.Int multiplications instead of 200.
+const digitChunk = 18
+
+// parseBigDigits parses a run of base-10 digits into a big.Int, working
+// digitChunk digits at a time instead of one digit (and one big.Int.Mul)
+// at a time.
+func parseBigDigits(digits string) *big.Int {
+	n := new(big.Int)
+	if digits == "" {
+		return n
+	}
+
+	chunkPow := new(big.Int).Exp(big.NewInt(10), big.NewInt(digitChunk), nil)
+	chunkVal := new(big.Int)
+
+	for i := 0; i < len(digits); i += digitChunk {
+		end := i + digitChunk
+		if end > len(digits) {
+			end = len(digits)
+		}
+		width := end - i
+
+		var v uint64
+		for _, c := range digits[i:end] {
+			v = v*10 + uint64(c-'0')
+		}
+
+		if i == 0 {
+			n.SetUint64(v)
+			continue
+		}
+
+		pow := chunkPow
+		if width != digitChunk {
+			pow = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(width)), nil)
+		}
+		n.Mul(n, pow)
+		n.Add(n, chunkVal.SetUint64(v))
+	}
+	return n
+}
@@ -0,0 +1,120 @@
+package mummy
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestHugeValueScanRoundTrip(t *testing.T) {
+	cases := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(42),
+		big.NewInt(-42),
+		new(big.Int).Exp(big.NewInt(10), big.NewInt(40), nil),
+	}
+
+	for _, want := range cases {
+		val, err := (Huge{Int: want}).Value()
+		if err != nil {
+			t.Fatalf("Value(%s): %v", want, err)
+		}
+
+		var got Huge
+		if err := got.Scan(val); err != nil {
+			t.Fatalf("Scan(%v): %v", val, err)
+		}
+		if got.Int.Cmp(want) != 0 {
+			t.Errorf("round trip of %s produced %s", want, got.Int)
+		}
+	}
+}
+
+func TestDecimalValueScanRoundTrip(t *testing.T) {
+	cases := []*big.Rat{
+		big.NewRat(0, 1),
+		big.NewRat(521, 100),
+		big.NewRat(-521, 100),
+		big.NewRat(1, 1),
+		big.NewRat(1, 2),
+		big.NewRat(1, 4),
+	}
+
+	for _, want := range cases {
+		val, err := (Decimal{Rat: want}).Value()
+		if err != nil {
+			t.Fatalf("Value(%s): %v", want.RatString(), err)
+		}
+
+		var got Decimal
+		if err := got.Scan(val); err != nil {
+			t.Fatalf("Scan(%v): %v", val, err)
+		}
+		if got.Rat.Cmp(want) != 0 {
+			t.Errorf("round trip of %s produced %s", want.RatString(), got.Rat.RatString())
+		}
+	}
+}
+
+func TestDecimalValueRejectsRepeatingDecimal(t *testing.T) {
+	_, err := (Decimal{Rat: big.NewRat(1, 3)}).Value()
+	if err == nil {
+		t.Fatal("expected an error for a denominator with a prime factor other than 2 or 5")
+	}
+	if _, ok := err.(*DenominatorError); !ok {
+		t.Fatalf("expected a *DenominatorError, got %T", err)
+	}
+}
+
+func TestHugeScanAcceptsSourceTypes(t *testing.T) {
+	sources := []interface{}{[]byte("123"), "123", int64(123), float64(123)}
+	for _, src := range sources {
+		var h Huge
+		if err := h.Scan(src); err != nil {
+			t.Fatalf("Scan(%v): %v", src, err)
+		}
+		if h.Int.Cmp(big.NewInt(123)) != 0 {
+			t.Errorf("Scan(%v) = %s, want 123", src, h.Int)
+		}
+	}
+}
+
+func randomDigits(r *rand.Rand, n int) string {
+	var sb strings.Builder
+	sb.WriteByte(byte('1' + r.Intn(9)))
+	for i := 1; i < n; i++ {
+		sb.WriteByte(byte('0' + r.Intn(10)))
+	}
+	return sb.String()
+}
+
+func FuzzHugeRoundTrip(f *testing.F) {
+	f.Add(int64(1), false)
+	f.Add(int64(2), true)
+
+	f.Fuzz(func(t *testing.T, seed int64, neg bool) {
+		digits := randomDigits(rand.New(rand.NewSource(seed)), 200)
+
+		want, ok := new(big.Int).SetString(digits, 10)
+		if !ok {
+			t.Fatalf("bad test input digits %q", digits)
+		}
+		if neg {
+			want.Neg(want)
+		}
+
+		val, err := (Huge{Int: want}).Value()
+		if err != nil {
+			t.Fatalf("Value: %v", err)
+		}
+
+		var got Huge
+		if err := got.Scan(val); err != nil {
+			t.Fatalf("Scan(%v): %v", val, err)
+		}
+		if got.Int.Cmp(want) != 0 {
+			t.Fatalf("round trip of %s produced %s", want, got.Int)
+		}
+	})
+}
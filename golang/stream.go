@@ -0,0 +1,84 @@
+package mummy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Encoder writes a stream of MUMMY-encoded values to an io.Writer, so
+// multiple values can be framed back-to-back on a socket or file without
+// materializing the whole payload up front.
+type Encoder struct {
+	w      io.Writer
+	buf    bytes.Buffer
+	order  binary.ByteOrder
+	varint bool
+}
+
+// NewEncoder returns an Encoder that writes to w, encoding multi-byte
+// fields big-endian until SetByteOrder says otherwise.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, order: binary.BigEndian}
+}
+
+// SetByteOrder changes the byte order this Encoder uses for multi-byte
+// fields. The reference mummy implementation is big-endian (the default);
+// little-endian is offered for interop with consumers that expect it.
+func (e *Encoder) SetByteOrder(order binary.ByteOrder) {
+	e.order = order
+}
+
+// UseVarint controls whether integers are encoded as MUMMY_TYPE_VARINT
+// instead of the fixed-width CHAR/SHORT/INT/LONG tags. Both forms carry a
+// one-byte type tag, so integers in -64..63 take two bytes either way;
+// varint is smaller for some larger magnitudes and up to a couple of bytes
+// larger for others. The decoder accepts either form regardless of this
+// setting, so it's purely an encode-time choice.
+func (e *Encoder) UseVarint(use bool) {
+	e.varint = use
+}
+
+// Encode writes the MUMMY encoding of v to the underlying writer.
+func (e *Encoder) Encode(v interface{}) error {
+	e.buf.Reset()
+	if err := feed_value(&e.buf, e.order, reflect.ValueOf(v), e.varint); err != nil {
+		return err
+	}
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+// Decoder reads a stream of MUMMY-encoded values from an io.Reader. It
+// reads only the type tag, then the length prefix, then exactly the
+// payload bytes for each value, so it never over-reads past one value
+// into the next.
+type Decoder struct {
+	r     io.Reader
+	order binary.ByteOrder
+}
+
+// NewDecoder returns a Decoder that reads from r, assuming multi-byte
+// fields are big-endian until SetByteOrder says otherwise.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, order: binary.BigEndian}
+}
+
+// SetByteOrder changes the byte order this Decoder expects for multi-byte
+// fields. The reference mummy implementation is big-endian (the default);
+// little-endian is offered for interop with producers that use it.
+func (d *Decoder) SetByteOrder(order binary.ByteOrder) {
+	d.order = order
+}
+
+// Decode reads the next MUMMY-encoded value from the underlying reader
+// into v, which must be a non-nil pointer.
+func (d *Decoder) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("mummy: Decode requires a non-nil pointer, got %T", v)
+	}
+	return dump_value(d.r, d.order, rv.Elem())
+}
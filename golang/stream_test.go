@@ -0,0 +1,37 @@
+package mummy
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncoderDecoderFraming(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+
+	values := []string{"first", "second", "third"}
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("Encode(%q): %v", v, err)
+		}
+	}
+
+	// A trailing byte the decoder must never touch, proving it stops
+	// exactly at the end of each value's payload.
+	buf.WriteByte(0xff)
+
+	dec := NewDecoder(&buf)
+	for _, want := range values {
+		var got string
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if got != want {
+			t.Errorf("Decode = %q, want %q", got, want)
+		}
+	}
+
+	if buf.Len() != 1 || buf.Bytes()[0] != 0xff {
+		t.Fatalf("decoder over-read into the trailing byte, buf = %v", buf.Bytes())
+	}
+}
@@ -0,0 +1,70 @@
+package mummy
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldTagInfo parses the "mummy" struct tag for f, mirroring the
+// "name,omitempty" convention used by encoding/json. skip is true for
+// unexported fields and fields tagged "-".
+func fieldTagInfo(f reflect.StructField) (name string, omitempty bool, skip bool) {
+	if f.PkgPath != "" {
+		return "", false, true
+	}
+
+	tag := f.Tag.Get("mummy")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name = f.Name
+	if tag == "" {
+		return name, false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		name = parts[0]
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+// structFieldsByName maps the MUMMY dict key each field of t encodes under
+// back to that field's index, for use while decoding.
+func structFieldsByName(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, skip := fieldTagInfo(t.Field(i))
+		if skip {
+			continue
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+// isEmptyValue reports whether v is the zero value for its type, for
+// struct fields tagged "omitempty".
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package mummy
+
+// MUMMY_TYPE_* are the single-byte type tags that prefix every encoded
+// value on the wire.
+const (
+	MUMMY_TYPE_NULL uint8 = iota
+	MUMMY_TYPE_BOOL
+	MUMMY_TYPE_CHAR
+	MUMMY_TYPE_SHORT
+	MUMMY_TYPE_INT
+	MUMMY_TYPE_LONG
+	MUMMY_TYPE_HUGE
+	MUMMY_TYPE_FLOAT
+	MUMMY_TYPE_DECIMAL
+	MUMMY_TYPE_SHORTSTR
+	MUMMY_TYPE_MEDSTR
+	MUMMY_TYPE_LONGSTR
+	MUMMY_TYPE_SHORTUTF8
+	MUMMY_TYPE_MEDUTF8
+	MUMMY_TYPE_LONGUTF8
+	MUMMY_TYPE_LIST
+	MUMMY_TYPE_DICT
+	MUMMY_TYPE_TIMESTAMP
+	MUMMY_TYPE_VARINT
+)
@@ -0,0 +1,421 @@
+package mummy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Unmarshal decodes MUMMY-encoded data into v, which must be a non-nil
+// pointer. It is the counterpart to Marshal: slices, maps, structs,
+// *big.Int, *big.Rat and time.Time all round-trip through their Marshal
+// encodings. A pointer to interface{} decodes into the generic Go type
+// that best matches the wire value (bool, int64, float64, string, []byte,
+// *big.Int, *big.Rat, time.Time, []interface{} or map[interface{}]interface{}).
+func Unmarshal(data []byte, v interface{}) error {
+	return NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func read_tag(r io.Reader) (uint8, error) {
+	var buf [1]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return buf[0], nil
+}
+
+func dump_value(r io.Reader, order binary.ByteOrder, target reflect.Value) error {
+	tag, err := read_tag(r)
+	if err != nil {
+		return err
+	}
+	return dump_tagged(r, order, tag, target)
+}
+
+func dump_tagged(r io.Reader, order binary.ByteOrder, tag uint8, target reflect.Value) error {
+	// *big.Int and *big.Rat are themselves the wire representations for
+	// HUGE and DECIMAL (see feed_value's Ptr case), so leave them alone
+	// rather than dereferencing down to a bare big.Int/big.Rat.
+	for target.Kind() == reflect.Ptr && target.Type() != bigIntType && target.Type() != bigRatType {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+
+	if target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		val, err := decode_any(r, order, tag)
+		if err != nil {
+			return err
+		}
+		if val == nil {
+			target.Set(reflect.Zero(target.Type()))
+		} else {
+			target.Set(reflect.ValueOf(val))
+		}
+		return nil
+	}
+
+	switch tag {
+	case MUMMY_TYPE_NULL:
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+
+	case MUMMY_TYPE_BOOL:
+		var n uint8
+		if err := binary.Read(r, order, &n); err != nil {
+			return err
+		}
+		if target.Kind() != reflect.Bool {
+			return fmt.Errorf("mummy: cannot decode bool into %s", target.Type())
+		}
+		target.SetBool(n != 0)
+		return nil
+
+	case MUMMY_TYPE_CHAR:
+		var n int8
+		if err := binary.Read(r, order, &n); err != nil {
+			return err
+		}
+		return set_int(target, int64(n))
+
+	case MUMMY_TYPE_SHORT:
+		var n int16
+		if err := binary.Read(r, order, &n); err != nil {
+			return err
+		}
+		return set_int(target, int64(n))
+
+	case MUMMY_TYPE_INT:
+		var n int32
+		if err := binary.Read(r, order, &n); err != nil {
+			return err
+		}
+		return set_int(target, int64(n))
+
+	case MUMMY_TYPE_LONG:
+		var n int64
+		if err := binary.Read(r, order, &n); err != nil {
+			return err
+		}
+		return set_int(target, n)
+
+	case MUMMY_TYPE_VARINT:
+		n, err := dump_varint(r)
+		if err != nil {
+			return err
+		}
+		return set_int(target, n)
+
+	case MUMMY_TYPE_HUGE:
+		num, err := dump_huge(r, order)
+		if err != nil {
+			return err
+		}
+		if target.Type() != bigIntType {
+			return fmt.Errorf("mummy: cannot decode huge into %s", target.Type())
+		}
+		target.Set(reflect.ValueOf(num))
+		return nil
+
+	case MUMMY_TYPE_DECIMAL:
+		rat, err := dump_decimal(r, order)
+		if err != nil {
+			return err
+		}
+		if target.Type() != bigRatType {
+			return fmt.Errorf("mummy: cannot decode decimal into %s", target.Type())
+		}
+		target.Set(reflect.ValueOf(rat))
+		return nil
+
+	case MUMMY_TYPE_FLOAT:
+		var f float64
+		if err := binary.Read(r, order, &f); err != nil {
+			return err
+		}
+		return set_float(target, f)
+
+	case MUMMY_TYPE_SHORTSTR, MUMMY_TYPE_MEDSTR, MUMMY_TYPE_LONGSTR:
+		buf, err := dump_string(r, order, tag)
+		if err != nil {
+			return err
+		}
+		if target.Kind() != reflect.Slice || target.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("mummy: cannot decode string into %s", target.Type())
+		}
+		target.SetBytes(buf)
+		return nil
+
+	case MUMMY_TYPE_SHORTUTF8, MUMMY_TYPE_MEDUTF8, MUMMY_TYPE_LONGUTF8:
+		buf, err := dump_string(r, order, tag)
+		if err != nil {
+			return err
+		}
+		if target.Kind() != reflect.String {
+			return fmt.Errorf("mummy: cannot decode utf8 into %s", target.Type())
+		}
+		target.SetString(string(buf))
+		return nil
+
+	case MUMMY_TYPE_TIMESTAMP:
+		t, err := dump_timestamp(r, order)
+		if err != nil {
+			return err
+		}
+		if target.Type() != timeType {
+			return fmt.Errorf("mummy: cannot decode timestamp into %s", target.Type())
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+
+	case MUMMY_TYPE_LIST:
+		return dump_list(r, order, target)
+
+	case MUMMY_TYPE_DICT:
+		return dump_dict(r, order, target)
+
+	default:
+		return fmt.Errorf("mummy: unknown type tag %d", tag)
+	}
+}
+
+func set_int(target reflect.Value, n int64) error {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		target.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		target.SetUint(uint64(n))
+	default:
+		return fmt.Errorf("mummy: cannot decode integer into %s", target.Type())
+	}
+	return nil
+}
+
+func set_float(target reflect.Value, f float64) error {
+	if target.Kind() != reflect.Float32 && target.Kind() != reflect.Float64 {
+		return fmt.Errorf("mummy: cannot decode float into %s", target.Type())
+	}
+	target.SetFloat(f)
+	return nil
+}
+
+func dump_list(r io.Reader, order binary.ByteOrder, target reflect.Value) error {
+	if target.Kind() != reflect.Slice {
+		return fmt.Errorf("mummy: cannot decode list into %s", target.Type())
+	}
+
+	count, err := read_count(r, order)
+	if err != nil {
+		return err
+	}
+
+	out := reflect.MakeSlice(target.Type(), count, count)
+	for i := 0; i < count; i++ {
+		if err := dump_value(r, order, out.Index(i)); err != nil {
+			return err
+		}
+	}
+	target.Set(out)
+	return nil
+}
+
+// read_count reads a list/dict element count and validates it before the
+// caller allocates anything sized by it. A negative count (corrupted data,
+// or a byte-order mismatch between peers) would otherwise reach
+// reflect.MakeSlice or make() and panic rather than error. When r exposes
+// its remaining unread byte count (as *bytes.Reader, the concrete reader
+// behind Unmarshal, does), a count larger than that can't possibly be
+// satisfied and is rejected up front instead of driving an oversized
+// allocation before the eventual read failure.
+func read_count(r io.Reader, order binary.ByteOrder) (int, error) {
+	var count int32
+	if err := binary.Read(r, order, &count); err != nil {
+		return 0, err
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("mummy: negative element count %d", count)
+	}
+	if lr, ok := r.(interface{ Len() int }); ok && int64(count) > int64(lr.Len()) {
+		return 0, fmt.Errorf("mummy: element count %d exceeds %d remaining bytes", count, lr.Len())
+	}
+	return int(count), nil
+}
+
+func dump_dict(r io.Reader, order binary.ByteOrder, target reflect.Value) error {
+	count, err := read_count(r, order)
+	if err != nil {
+		return err
+	}
+
+	switch target.Kind() {
+	case reflect.Map:
+		if target.IsNil() {
+			target.Set(reflect.MakeMapWithSize(target.Type(), count))
+		}
+		keyType := target.Type().Key()
+		elemType := target.Type().Elem()
+		for i := 0; i < count; i++ {
+			kv := reflect.New(keyType).Elem()
+			if err := dump_value(r, order, kv); err != nil {
+				return err
+			}
+			vv := reflect.New(elemType).Elem()
+			if err := dump_value(r, order, vv); err != nil {
+				return err
+			}
+			target.SetMapIndex(kv, vv)
+		}
+		return nil
+
+	case reflect.Struct:
+		fields := structFieldsByName(target.Type())
+		for i := 0; i < count; i++ {
+			var key string
+			if err := dump_value(r, order, reflect.ValueOf(&key).Elem()); err != nil {
+				return err
+			}
+			idx, ok := fields[key]
+			if !ok {
+				var discard interface{}
+				if err := dump_value(r, order, reflect.ValueOf(&discard).Elem()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := dump_value(r, order, target.Field(idx)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("mummy: cannot decode dict into %s", target.Type())
+	}
+}
+
+// decode_any decodes the value tagged by tag into the generic Go type
+// that best represents it, for use when the caller's target is interface{}.
+func decode_any(r io.Reader, order binary.ByteOrder, tag uint8) (interface{}, error) {
+	switch tag {
+	case MUMMY_TYPE_NULL:
+		return nil, nil
+
+	case MUMMY_TYPE_BOOL:
+		var n uint8
+		if err := binary.Read(r, order, &n); err != nil {
+			return nil, err
+		}
+		return n != 0, nil
+
+	case MUMMY_TYPE_CHAR:
+		var n int8
+		if err := binary.Read(r, order, &n); err != nil {
+			return nil, err
+		}
+		return int64(n), nil
+
+	case MUMMY_TYPE_SHORT:
+		var n int16
+		if err := binary.Read(r, order, &n); err != nil {
+			return nil, err
+		}
+		return int64(n), nil
+
+	case MUMMY_TYPE_INT:
+		var n int32
+		if err := binary.Read(r, order, &n); err != nil {
+			return nil, err
+		}
+		return int64(n), nil
+
+	case MUMMY_TYPE_LONG:
+		var n int64
+		if err := binary.Read(r, order, &n); err != nil {
+			return nil, err
+		}
+		return n, nil
+
+	case MUMMY_TYPE_VARINT:
+		return dump_varint(r)
+
+	case MUMMY_TYPE_HUGE:
+		return dump_huge(r, order)
+
+	case MUMMY_TYPE_DECIMAL:
+		return dump_decimal(r, order)
+
+	case MUMMY_TYPE_FLOAT:
+		var f float64
+		if err := binary.Read(r, order, &f); err != nil {
+			return nil, err
+		}
+		return f, nil
+
+	case MUMMY_TYPE_SHORTSTR, MUMMY_TYPE_MEDSTR, MUMMY_TYPE_LONGSTR:
+		return dump_string(r, order, tag)
+
+	case MUMMY_TYPE_SHORTUTF8, MUMMY_TYPE_MEDUTF8, MUMMY_TYPE_LONGUTF8:
+		buf, err := dump_string(r, order, tag)
+		if err != nil {
+			return nil, err
+		}
+		return string(buf), nil
+
+	case MUMMY_TYPE_TIMESTAMP:
+		return dump_timestamp(r, order)
+
+	case MUMMY_TYPE_LIST:
+		count, err := read_count(r, order)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, count)
+		for i := range list {
+			v, err := decode_tagged_any(r, order)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+
+	case MUMMY_TYPE_DICT:
+		count, err := read_count(r, order)
+		if err != nil {
+			return nil, err
+		}
+		dict := make(map[interface{}]interface{}, count)
+		for i := 0; i < count; i++ {
+			k, err := decode_tagged_any(r, order)
+			if err != nil {
+				return nil, err
+			}
+			// []byte (a SHORTSTR/MEDSTR/LONGSTR key) isn't comparable and
+			// would panic the map insert below; string-ify it the same
+			// way a UTF8-tagged key already comes back as a string.
+			if kb, ok := k.([]byte); ok {
+				k = string(kb)
+			}
+			v, err := decode_tagged_any(r, order)
+			if err != nil {
+				return nil, err
+			}
+			dict[k] = v
+		}
+		return dict, nil
+
+	default:
+		return nil, fmt.Errorf("mummy: unknown type tag %d", tag)
+	}
+}
+
+func decode_tagged_any(r io.Reader, order binary.ByteOrder) (interface{}, error) {
+	tag, err := read_tag(r)
+	if err != nil {
+		return nil, err
+	}
+	return decode_any(r, order, tag)
+}
@@ -0,0 +1,62 @@
+package mummy
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFeedDumpVarintRoundTrip(t *testing.T) {
+	cases := []int64{0, 1, -1, 63, -64, 64, 1000000, -1000000, 1<<62 - 1, -(1 << 62)}
+
+	for _, want := range cases {
+		var buf bytes.Buffer
+		if err := feed_varint(&buf, want); err != nil {
+			t.Fatalf("feed_varint(%d): %v", want, err)
+		}
+		buf.Next(1) // drop the type tag
+
+		got, err := dump_varint(&buf)
+		if err != nil {
+			t.Fatalf("dump_varint(%d): %v", want, err)
+		}
+		if got != want {
+			t.Errorf("round trip of %d produced %d", want, got)
+		}
+	}
+}
+
+func TestFeedVarintSmallValueTakesOnePayloadByte(t *testing.T) {
+	var buf bytes.Buffer
+	if err := feed_varint(&buf, 5); err != nil {
+		t.Fatalf("feed_varint: %v", err)
+	}
+	// tag byte + a single payload byte for small magnitudes
+	if buf.Len() != 2 {
+		t.Errorf("encoded length = %d, want 2", buf.Len())
+	}
+}
+
+func TestEncoderUseVarintDecodesTransparently(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.UseVarint(true)
+	if err := enc.Encode(int64(42)); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	var got int64
+	if err := NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got != 42 {
+		t.Errorf("got %d, want 42", got)
+	}
+}
+
+func TestDumpVarintCapsAtTenBytes(t *testing.T) {
+	// 11 continuation bytes: never terminates within the 10-byte cap.
+	buf := bytes.NewBuffer(bytes.Repeat([]byte{0x80}, 11))
+	if _, err := dump_varint(buf); err == nil {
+		t.Fatal("expected an error for a varint exceeding 10 bytes")
+	}
+}